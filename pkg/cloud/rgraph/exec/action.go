@@ -0,0 +1,184 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// ActionType classifies what an Action does, primarily for logging,
+// dry-run summaries and CloudEvents/Pub-Sub payloads.
+type ActionType string
+
+const (
+	// ActionTypeCreate creates a resource.
+	ActionTypeCreate ActionType = "Create"
+	// ActionTypeUpdate updates an existing resource.
+	ActionTypeUpdate ActionType = "Update"
+	// ActionTypeDelete deletes a resource.
+	ActionTypeDelete ActionType = "Delete"
+	// ActionTypeMeta is a synthetic Action that only exists to
+	// coordinate Events between other Actions (e.g. EventAction).
+	ActionTypeMeta ActionType = "Meta"
+	// ActionTypeCustom is a caller-provided Action whose behavior is
+	// not otherwise described by this package.
+	ActionTypeCustom ActionType = "Custom"
+	// ActionTypeCustomRun is a caller-provided Action whose work is
+	// actually performed by an out-of-process controller; see
+	// AsyncAction.
+	ActionTypeCustomRun ActionType = "CustomRun"
+)
+
+// ActionMetadata describes an Action for logging, tracing and
+// notification purposes. It is intentionally decoupled from the
+// Action's runtime behavior.
+type ActionMetadata struct {
+	// Name uniquely identifies this Action within a graph.
+	Name string
+	// Type is the kind of Action this is.
+	Type ActionType
+	// Summary is a short, human readable description of the work
+	// this Action performs.
+	Summary string
+	// Mode controls how the Executor treats this Action: whether it is
+	// actually run, only planned, or only produces a warning. The zero
+	// value behaves as EnforcementEnforce. A graph-level policy
+	// supplied via WithEnforcementPolicy can override this per-Action
+	// value.
+	Mode EnforcementMode
+	// Scope names which part of a run Mode applies to. The zero value
+	// behaves as EnforcementScopeAll. The Executor applies Mode the same
+	// way for every Scope value (see EnforcementScope).
+	Scope EnforcementScope
+}
+
+// Action is a unit of work in the graph executed by an Executor.
+// Actions are connected to each other via the Events they wait on and
+// emit; the Executor runs an Action once CanRun() is true.
+type Action interface {
+	fmt.Stringer
+
+	// Metadata describes the Action.
+	Metadata() *ActionMetadata
+	// CanRun returns true if all of the Action's pending Events have
+	// been signaled.
+	CanRun() bool
+	// Signal notifies the Action that e has occurred. It returns true
+	// if e was one of the Events the Action was waiting on.
+	Signal(e Event) bool
+	// PendingEvents returns the Events the Action is still waiting on.
+	PendingEvents() []Event
+	// DoneEvents returns the Events the Action has already seen.
+	DoneEvents() []Event
+	// DryRun returns the Events this Action would emit, without doing
+	// any work or touching the cloud.
+	DryRun() []Event
+	// Run performs the Action's work and returns the Events it has
+	// emitted.
+	Run(ctx context.Context, c cloud.Cloud) ([]Event, error)
+}
+
+// ActionResourceIDs is optionally implemented by Actions that operate
+// on specific cloud resources, so the Executor can attribute
+// notifications (CloudEvents, Pub/Sub messages) to those resources.
+type ActionResourceIDs interface {
+	ResourceIDs() []*cloud.ResourceID
+}
+
+// ActionBase implements the Event bookkeeping common to all Actions.
+// Actions embed this and only need to implement String, Metadata,
+// DryRun and Run.
+type ActionBase struct {
+	// Want is the set of Events this Action has not yet seen.
+	Want []Event
+	// Done is the set of Events this Action has seen, in the order
+	// they were signaled.
+	Done []Event
+}
+
+// Signal notifies the Action that e has occurred.
+func (a *ActionBase) Signal(e Event) bool {
+	for i, w := range a.Want {
+		if w.Equal(e) {
+			a.Want = append(a.Want[:i], a.Want[i+1:]...)
+			a.Done = append(a.Done, e)
+			return true
+		}
+	}
+	return false
+}
+
+// PendingEvents returns the Events this Action is still waiting on.
+func (a *ActionBase) PendingEvents() []Event { return a.Want }
+
+// DoneEvents returns the Events this Action has already seen.
+func (a *ActionBase) DoneEvents() []Event { return a.Done }
+
+// CanRun returns true once every Event this Action was waiting on has
+// been signaled.
+func (a *ActionBase) CanRun() bool { return len(a.Want) == 0 }
+
+// EventAction is a synthetic Action that does no work of its own; it
+// simply emits a fixed set of Events when run. It is most commonly
+// used as a graph root to seed Events that describe precondition
+// state (e.g. "resource X already exists").
+type EventAction struct {
+	ActionBase
+	events []Event
+}
+
+// NewEventAction returns an Action that emits events and has no
+// prerequisites.
+func NewEventAction(events []Event) *EventAction {
+	return &EventAction{events: events}
+}
+
+// NewExistsAction returns an EventAction that signals that the
+// resource identified by id already exists.
+func NewExistsAction(id *cloud.ResourceID) *EventAction {
+	return NewEventAction([]Event{&existsEvent{id: id}})
+}
+
+func (a *EventAction) String() string { return fmt.Sprintf("EventAction(%v)", a.events) }
+
+func (a *EventAction) DryRun() []Event { return a.events }
+
+func (a *EventAction) Run(context.Context, cloud.Cloud) ([]Event, error) { return a.events, nil }
+
+func (a *EventAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{
+		Name:    a.String(),
+		Type:    ActionTypeMeta,
+		Summary: "Action that only emits Events; performs no work",
+	}
+}
+
+// existsEvent signals that the resource identified by id already
+// exists.
+type existsEvent struct {
+	id *cloud.ResourceID
+}
+
+func (e *existsEvent) String() string { return fmt.Sprintf("Exists(%s)", e.id) }
+
+func (e *existsEvent) Equal(o Event) bool {
+	other, ok := o.(*existsEvent)
+	return ok && e.id.Equal(other.id)
+}
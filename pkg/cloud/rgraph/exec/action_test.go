@@ -32,6 +32,10 @@ type testAction struct {
 	name   string
 	events []Event
 	err    error
+	mode   EnforcementMode
+	scope  EnforcementScope
+
+	ran bool
 }
 
 func (a *testAction) String() string {
@@ -43,6 +47,7 @@ func (a *testAction) DryRun() []Event {
 }
 
 func (a *testAction) Run(context.Context, cloud.Cloud) ([]Event, error) {
+	a.ran = true
 	return a.events, a.err
 }
 
@@ -51,6 +56,8 @@ func (a *testAction) Metadata() *ActionMetadata {
 		Name:    fmt.Sprintf("%s(%v)", a.name, a.events),
 		Type:    ActionTypeCustom,
 		Summary: "Action used for testing",
+		Mode:    a.mode,
+		Scope:   a.scope,
 	}
 }
 
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// Handle identifies an out-of-process operation started by
+// AsyncAction.Start, to be passed back to AsyncAction.Poll.
+type Handle string
+
+// State is the lifecycle state of an AsyncAction's out-of-process
+// operation.
+type State string
+
+const (
+	// StatePending means the operation has been accepted but has not
+	// started doing work.
+	StatePending State = "Pending"
+	// StateRunning means the operation is in progress.
+	StateRunning State = "Running"
+	// StateDone means the operation finished successfully.
+	StateDone State = "Done"
+	// StateFailed means the operation finished with an error.
+	StateFailed State = "Failed"
+)
+
+// AsyncAction is implemented by Actions whose work is performed by an
+// out-of-process controller rather than synchronously within Run, the
+// same role Tekton's custom Run resources play for third-party
+// controllers. The Executor calls Start once and then calls Poll
+// repeatedly (across separate ticks, never blocking a worker on it)
+// until it reports StateDone or StateFailed.
+type AsyncAction interface {
+	Action
+
+	// Start hands the work off to the external controller and returns
+	// a Handle identifying it. Start must not block waiting for the
+	// work to finish.
+	Start(ctx context.Context, c cloud.Cloud) (Handle, error)
+	// Poll reports the current state of the operation identified by
+	// h. While State is StatePending or StateRunning, events must be
+	// empty. Once State is StateDone, events are the Events the
+	// completed operation emitted; the Executor signals them to the
+	// rest of the graph exactly as it would for a synchronous Action.
+	Poll(ctx context.Context, h Handle) (State, []Event, error)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// fakeAsyncAction is an AsyncAction that walks through a fixed
+// sequence of States, one per Poll call, for use in tests.
+type fakeAsyncAction struct {
+	ActionBase
+	name    string
+	states  []State
+	polls   int
+	started bool
+}
+
+func (a *fakeAsyncAction) String() string { return fmt.Sprintf("fakeAsyncAction(%s)", a.name) }
+
+func (a *fakeAsyncAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{Name: a.name, Type: ActionTypeCustomRun}
+}
+
+func (a *fakeAsyncAction) DryRun() []Event { return nil }
+
+func (a *fakeAsyncAction) Run(context.Context, cloud.Cloud) ([]Event, error) {
+	return nil, fmt.Errorf("fakeAsyncAction(%s): Run called directly", a.name)
+}
+
+func (a *fakeAsyncAction) Start(context.Context, cloud.Cloud) (Handle, error) {
+	a.started = true
+	return Handle(a.name), nil
+}
+
+func (a *fakeAsyncAction) Poll(context.Context, Handle) (State, []Event, error) {
+	s := a.states[a.polls]
+	if a.polls < len(a.states)-1 {
+		a.polls++
+	}
+	if s == StateDone {
+		return s, []Event{StringEvent(a.name + "-done")}, nil
+	}
+	return s, nil, nil
+}
+
+func TestExecutorAsyncActionAcrossTicks(t *testing.T) {
+	a := &fakeAsyncAction{name: "a", states: []State{StatePending, StateRunning, StateDone}}
+	dependent := &testAction{name: "dependent", events: []Event{}}
+	dependent.Want = []Event{StringEvent("a-done")}
+
+	ex, err := NewExecutor([]Action{a, dependent})
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+
+	// Tick 1: a.Start is called and it is parked; dependent cannot run.
+	done, err := ex.Tick(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Tick() #1 = %v, want nil", err)
+	}
+	if done {
+		t.Fatalf("Tick() #1 done = true, want false")
+	}
+	if !a.started {
+		t.Fatalf("a.started = false, want true")
+	}
+	if dependent.CanRun() {
+		t.Fatalf("dependent.CanRun() = true, want false")
+	}
+
+	// Tick 2: a reports StateRunning, nothing changes.
+	done, err = ex.Tick(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Tick() #2 = %v, want nil", err)
+	}
+	if done {
+		t.Fatalf("Tick() #2 done = true, want false")
+	}
+
+	// Tick 3: a reports StateDone; its Event unblocks dependent, but
+	// dependent itself hasn't run yet this tick.
+	done, err = ex.Tick(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Tick() #3 = %v, want nil", err)
+	}
+	if done {
+		t.Fatalf("Tick() #3 done = true, want false")
+	}
+	if !dependent.CanRun() {
+		t.Fatalf("dependent.CanRun() = false, want true")
+	}
+
+	// Tick 4: dependent runs.
+	done, err = ex.Tick(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Tick() #4 = %v, want nil", err)
+	}
+	if !done {
+		t.Fatalf("Tick() #4 done = false, want true")
+	}
+
+	if len(ex.result.Completed) != 2 {
+		t.Fatalf("len(ex.result.Completed) = %d, want 2", len(ex.result.Completed))
+	}
+}
+
+func TestExecutorAsyncActionFailure(t *testing.T) {
+	a := &fakeAsyncAction{name: "a", states: []State{StateFailed}}
+
+	ex, err := NewExecutor([]Action{a})
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+
+	if _, err := ex.Tick(context.Background(), nil); err != nil {
+		t.Fatalf("Tick() #1 = %v, want nil", err)
+	}
+	done, err := ex.Tick(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Tick() #2 = %v, want nil", err)
+	}
+	if !done {
+		t.Fatalf("Tick() #2 done = false, want true")
+	}
+	if len(ex.result.Errors) != 1 {
+		t.Fatalf("len(ex.result.Errors) = %d, want 1", len(ex.result.Errors))
+	}
+}
+
+func TestExecutorRunWaitsPollIntervalBetweenTicks(t *testing.T) {
+	a := &fakeAsyncAction{name: "a", states: []State{StatePending, StateRunning, StateDone}}
+
+	const pollInterval = 10 * time.Millisecond
+	ex, err := NewExecutor([]Action{a}, WithPollInterval(pollInterval))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+
+	start := time.Now()
+	if _, err := ex.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	elapsed := time.Since(start)
+
+	// a.Poll reports StateDone on its third call, so Run must wait at
+	// least two poll intervals: it has nothing runnable but a parked
+	// after the first tick starts it.
+	if elapsed < 2*pollInterval {
+		t.Fatalf("Run() took %v, want at least %v (Run must back off between polls instead of busy-spinning)", elapsed, 2*pollInterval)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// CloudEvents type values published for Action lifecycle transitions.
+// These mirror the CloudEvents-based completion notifications Tekton
+// emits for TaskRuns, so that the same class of controller/audit-log
+// consumer can subscribe to either.
+const (
+	eventSource = "dev.k8s-cloud-provider/exec"
+
+	EventTypeActionStarted       = "dev.k8s-cloud-provider.exec.action.started"
+	EventTypeActionSucceeded     = "dev.k8s-cloud-provider.exec.action.succeeded"
+	EventTypeActionFailed        = "dev.k8s-cloud-provider.exec.action.failed"
+	EventTypeActionEventSignaled = "dev.k8s-cloud-provider.exec.action.event_signaled"
+)
+
+// EventEmitter publishes CloudEvents describing Action lifecycle
+// transitions observed by an Executor. Implementations must be safe
+// for concurrent use.
+type EventEmitter interface {
+	// Emit publishes ev. Notification delivery is best-effort: a
+	// non-nil error is discarded by the Executor and does not fail the
+	// Action or abort the run.
+	Emit(ctx context.Context, ev cloudevents.Event) error
+}
+
+// NoopEventEmitter discards every event. It is the Executor's default
+// EventEmitter when WithEventEmitter is not supplied.
+type NoopEventEmitter struct{}
+
+// Emit implements EventEmitter.
+func (*NoopEventEmitter) Emit(ctx context.Context, ev cloudevents.Event) error { return nil }
+
+// HTTPEventEmitter POSTs each event as a CloudEvents JSON document to
+// Endpoint. Emit hands the POST off to a goroutine and returns
+// immediately, so a slow or unreachable Endpoint can never block
+// Executor.Tick's hot loop the way a synchronous call would; this
+// mirrors the Publish/Flush split GCPPublisher uses for the same
+// reason. Call Flush to observe delivery errors from Emit calls made
+// so far.
+type HTTPEventEmitter struct {
+	Endpoint string
+	Client   *http.Client
+
+	mu      sync.Mutex
+	pending []chan error
+}
+
+// NewHTTPEventEmitter returns an EventEmitter that POSTs to endpoint
+// using http.DefaultClient.
+func NewHTTPEventEmitter(endpoint string) *HTTPEventEmitter {
+	return &HTTPEventEmitter{Endpoint: endpoint, Client: http.DefaultClient}
+}
+
+// Emit implements EventEmitter. The returned error is always nil; the
+// POST happens on a goroutine after Emit has already returned, so any
+// delivery error surfaces from Flush instead.
+func (h *HTTPEventEmitter) Emit(ctx context.Context, ev cloudevents.Event) error {
+	done := make(chan error, 1)
+	h.mu.Lock()
+	h.pending = append(h.pending, done)
+	h.mu.Unlock()
+
+	go func() { done <- h.post(ctx, ev) }()
+	return nil
+}
+
+// Flush waits for every Emit call so far to finish its POST,
+// returning the first error encountered, if any. Events Emitted
+// concurrently with Flush may or may not be waited on.
+func (h *HTTPEventEmitter) Flush() error {
+	h.mu.Lock()
+	pending := h.pending
+	h.pending = nil
+	h.mu.Unlock()
+
+	var firstErr error
+	for _, done := range pending {
+		if err := <-done; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// post performs the actual POST for ev, returning any error
+// encountered building or sending the request.
+func (h *HTTPEventEmitter) post(ctx context.Context, ev cloudevents.Event) error {
+	b, err := ev.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("HTTPEventEmitter: marshal event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Endpoint, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("HTTPEventEmitter: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTPEventEmitter: post to %s: %w", h.Endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("HTTPEventEmitter: post to %s: status %s", h.Endpoint, resp.Status)
+	}
+	return nil
+}
+
+// WithEventEmitter configures the Executor to publish Action lifecycle
+// transitions to em as CloudEvents.
+func WithEventEmitter(em EventEmitter) ExecutorOption {
+	return func(o *executorOptions) error {
+		o.eventEmitter = em
+		return nil
+	}
+}
+
+// actionEventData is the CloudEvents `data` payload for Action
+// lifecycle events.
+type actionEventData struct {
+	Metadata    *ActionMetadata     `json:"metadata"`
+	ResourceIDs []*cloud.ResourceID `json:"resourceIDs,omitempty"`
+	// Events holds each Event's String() form rather than the Event
+	// itself: most Event implementations (e.g. existsEvent) keep their
+	// state in unexported fields, so marshaling the interface value
+	// directly would silently serialize them as {}.
+	Events []string `json:"events,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// emitActionEvent builds and publishes a lifecycle CloudEvent for a.
+// em == nil is treated the same as a NoopEventEmitter.
+func emitActionEvent(ctx context.Context, em EventEmitter, eventType string, a Action, resourceIDs []*cloud.ResourceID, events []Event, runErr error) {
+	if em == nil {
+		return
+	}
+
+	ev := cloudevents.NewEvent()
+	ev.SetSource(eventSource)
+	ev.SetType(eventType)
+	ev.SetID(fmt.Sprintf("%s/%d", a.Metadata().Name, time.Now().UnixNano()))
+	ev.SetTime(time.Now())
+
+	data := actionEventData{
+		Metadata:    a.Metadata(),
+		ResourceIDs: resourceIDs,
+		Events:      eventStrings(events),
+	}
+	if runErr != nil {
+		data.Error = runErr.Error()
+	}
+	if err := ev.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		// There is no good way to surface this without changing the
+		// Executor's Run signature; dropping the notification is
+		// preferable to failing the Action it describes.
+		return
+	}
+
+	// Emission is best-effort and must never fail the Executor;
+	// swallow the error here.
+	_ = em.Emit(ctx, ev)
+}
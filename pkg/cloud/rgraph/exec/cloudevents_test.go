@@ -0,0 +1,134 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// fakeEventEmitter records every Emit call for inspection by tests.
+type fakeEventEmitter struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (f *fakeEventEmitter) Emit(ctx context.Context, ev cloudevents.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, ev)
+	return nil
+}
+
+func TestHTTPEventEmitter(t *testing.T) {
+	var gotContentType string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		buf := make([]byte, r.ContentLength)
+		r.Body.Read(buf)
+		gotBody = buf
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	em := NewHTTPEventEmitter(srv.URL)
+	ev := cloudevents.NewEvent()
+	ev.SetSource(eventSource)
+	ev.SetType(EventTypeActionStarted)
+	ev.SetID("test-id")
+
+	if err := em.Emit(context.Background(), ev); err != nil {
+		t.Fatalf("Emit() = %v, want nil", err)
+	}
+	if err := em.Flush(); err != nil {
+		t.Fatalf("Flush() = %v, want nil", err)
+	}
+	if gotContentType != "application/cloudevents+json" {
+		t.Errorf("Content-Type = %q, want application/cloudevents+json", gotContentType)
+	}
+	if len(gotBody) == 0 {
+		t.Errorf("request body was empty")
+	}
+}
+
+func TestHTTPEventEmitterError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	em := NewHTTPEventEmitter(srv.URL)
+	if err := em.Emit(context.Background(), cloudevents.NewEvent()); err != nil {
+		t.Fatalf("Emit() = %v, want nil (errors surface from Flush)", err)
+	}
+	if err := em.Flush(); err == nil {
+		t.Errorf("Flush() = nil, want error for 500 response")
+	}
+}
+
+// TestHTTPEventEmitterEmitDoesNotBlock proves that Emit returns before
+// the server responds, which is what keeps a slow Endpoint from
+// blocking Executor.Tick's hot loop.
+func TestHTTPEventEmitterEmitDoesNotBlock(t *testing.T) {
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	defer close(unblock)
+
+	em := NewHTTPEventEmitter(srv.URL)
+	done := make(chan error, 1)
+	go func() { done <- em.Emit(context.Background(), cloudevents.NewEvent()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Emit() = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Emit() blocked for 2s waiting on a server response it should never wait for")
+	}
+}
+
+func TestEmitActionEvent(t *testing.T) {
+	em := &fakeEventEmitter{}
+	a := &testAction{name: "a", events: []Event{StringEvent("done")}}
+
+	emitActionEvent(context.Background(), em, EventTypeActionSucceeded, a, nil, a.events, nil)
+
+	if len(em.events) != 1 {
+		t.Fatalf("len(em.events) = %d, want 1", len(em.events))
+	}
+	if got, want := em.events[0].Type(), EventTypeActionSucceeded; got != want {
+		t.Errorf("Type() = %q, want %q", got, want)
+	}
+}
+
+func TestEmitActionEventNilEmitter(t *testing.T) {
+	a := &testAction{name: "a"}
+	// Must not panic when no EventEmitter is configured.
+	emitActionEvent(context.Background(), nil, EventTypeActionStarted, a, nil, nil, nil)
+}
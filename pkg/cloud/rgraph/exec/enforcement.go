@@ -0,0 +1,128 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnforcementMode controls how the Executor treats an Action,
+// borrowing the scoped-enforcement idea from Gatekeeper constraints:
+// a graph can mix Actions that are actually executed with ones that
+// are only planned or only warned about.
+type EnforcementMode string
+
+const (
+	// EnforcementEnforce runs the Action normally. This is the
+	// behavior of the zero value.
+	EnforcementEnforce EnforcementMode = "Enforce"
+	// EnforcementDryrun replaces Run with DryRun: the Action's Events
+	// are still produced and signaled to the rest of the graph, but no
+	// work is performed.
+	EnforcementDryrun EnforcementMode = "Dryrun"
+	// EnforcementWarn behaves like EnforcementDryrun, but additionally
+	// records a WarningEvent in the Result so callers can surface that
+	// a policy prevented the Action from actually running.
+	EnforcementWarn EnforcementMode = "Warn"
+	// EnforcementDeny aborts the entire Run before any Action
+	// executes, returning a DenyError listing every Action whose
+	// effective mode is Deny.
+	EnforcementDeny EnforcementMode = "Deny"
+)
+
+// EnforcementScope limits which part of a Run an EnforcementMode
+// applies to. Executor.Run has no separate planning phase today, so
+// every scope currently enforces Mode identically: a policy scoped to
+// EnforcementScopePlan (e.g. "never delete production forwarding
+// rules", warned about before this package grows a planning phase)
+// must not be silently weakened into running for real in the
+// meantime.
+type EnforcementScope string
+
+const (
+	// EnforcementScopeAll applies the mode to the whole Run. This is
+	// the behavior of the zero value.
+	EnforcementScopeAll EnforcementScope = "All"
+	// EnforcementScopePlan applies the mode to planning, which today
+	// includes Executor.Run: a Plan-scoped Action in Dryrun or Warn
+	// mode is dry-run, not actually executed.
+	EnforcementScopePlan EnforcementScope = "Plan"
+	// EnforcementScopeExecute applies the mode to execution, the same
+	// as EnforcementScopeAll.
+	EnforcementScopeExecute EnforcementScope = "Execute"
+)
+
+// EnforcementPolicy computes the EnforcementMode the Executor should
+// use for a, overriding whatever is set on a.Metadata().Mode. It lets
+// callers inject cross-cutting policy (e.g. "never delete production
+// forwarding rules") without rewriting every Action. A policy that
+// returns the empty EnforcementMode for a leaves a.Metadata().Mode in
+// effect.
+type EnforcementPolicy func(a Action) EnforcementMode
+
+// WithEnforcementPolicy configures the Executor to consult p for every
+// Action's effective EnforcementMode, in place of the mode set on its
+// ActionMetadata.
+func WithEnforcementPolicy(p EnforcementPolicy) ExecutorOption {
+	return func(o *executorOptions) error {
+		o.policy = p
+		return nil
+	}
+}
+
+// effectiveMode returns the EnforcementMode the Executor should use
+// for a: the configured policy's result if one is set and it returns a
+// non-empty mode, otherwise the mode from a's ActionMetadata,
+// defaulting to EnforcementEnforce.
+func effectiveMode(a Action, policy EnforcementPolicy) EnforcementMode {
+	if policy != nil {
+		if m := policy(a); m != "" {
+			return m
+		}
+	}
+	if m := a.Metadata().Mode; m != "" {
+		return m
+	}
+	return EnforcementEnforce
+}
+
+// WarningEvent records that a would normally have run, but was only
+// dry-run because its effective EnforcementMode was Warn.
+type WarningEvent struct {
+	Action Action
+	Events []Event
+}
+
+func (w *WarningEvent) String() string {
+	return fmt.Sprintf("Warning(%s, would emit %v)", w.Action, w.Events)
+}
+
+// DenyError is returned by Executor.Run when one or more Actions in
+// the graph have an effective EnforcementMode of Deny. No Action in
+// the graph is run.
+type DenyError struct {
+	Actions []Action
+}
+
+func (e *DenyError) Error() string {
+	names := make([]string, 0, len(e.Actions))
+	for _, a := range e.Actions {
+		names = append(names, a.String())
+	}
+	return fmt.Sprintf("Executor.Run: denied by enforcement policy: %s", strings.Join(names, ", "))
+}
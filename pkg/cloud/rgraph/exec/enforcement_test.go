@@ -0,0 +1,163 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestExecutorEnforcementWarn(t *testing.T) {
+	a := &testAction{name: "a", events: []Event{StringEvent("a-done")}}
+
+	policy := func(act Action) EnforcementMode {
+		if act.Metadata().Name == a.Metadata().Name {
+			return EnforcementWarn
+		}
+		return EnforcementEnforce
+	}
+
+	ex, err := NewExecutor([]Action{a}, WithEnforcementPolicy(policy))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if len(result.Warnings) != 1 {
+		t.Fatalf("len(result.Warnings) = %d, want 1", len(result.Warnings))
+	}
+	if result.Warnings[0].Action != Action(a) {
+		t.Errorf("Warnings[0].Action = %v, want %v", result.Warnings[0].Action, a)
+	}
+}
+
+func TestExecutorEnforcementDenyShortCircuits(t *testing.T) {
+	runnable := &testAction{name: "runnable", events: []Event{StringEvent("x")}}
+	denied := &testAction{name: "denied", err: errors.New("should never run")}
+
+	policy := func(act Action) EnforcementMode {
+		if act.Metadata().Name == denied.Metadata().Name {
+			return EnforcementDeny
+		}
+		return EnforcementEnforce
+	}
+
+	ex, err := NewExecutor([]Action{runnable, denied}, WithEnforcementPolicy(policy))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background(), nil)
+	if err == nil {
+		t.Fatalf("Run() = nil error, want DenyError")
+	}
+	var denyErr *DenyError
+	if !errors.As(err, &denyErr) {
+		t.Fatalf("Run() error = %v, want *DenyError", err)
+	}
+	if len(denyErr.Actions) != 1 || denyErr.Actions[0] != Action(denied) {
+		t.Errorf("denyErr.Actions = %v, want [%v]", denyErr.Actions, denied)
+	}
+	if result != nil {
+		t.Errorf("result = %v, want nil", result)
+	}
+}
+
+func TestExecutorEnforcementDenyViaTick(t *testing.T) {
+	denied := &testAction{name: "denied", err: errors.New("should never run")}
+
+	policy := func(act Action) EnforcementMode {
+		return EnforcementDeny
+	}
+
+	ex, err := NewExecutor([]Action{denied}, WithEnforcementPolicy(policy))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+
+	// A caller driving the Executor directly via Tick, bypassing Run,
+	// must still be refused a denied Action.
+	done, err := ex.Tick(context.Background(), nil)
+	if done {
+		t.Fatalf("Tick() done = true, want false")
+	}
+	var denyErr *DenyError
+	if !errors.As(err, &denyErr) {
+		t.Fatalf("Tick() error = %v, want *DenyError", err)
+	}
+	if len(ex.result.Errors) != 0 {
+		t.Errorf("ex.result.Errors = %v, want none (denied Action must not run)", ex.result.Errors)
+	}
+}
+
+// TestExecutorEnforcementScope proves that EnforcementScopePlan does
+// not weaken Mode: a Plan-scoped Action used for a policy like "never
+// delete production forwarding rules" must actually be suppressed by
+// Executor.Run, the same as one scoped to Execute or All.
+func TestExecutorEnforcementScope(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		mode     EnforcementMode
+		scope    EnforcementScope
+		wantWarn int
+	}{
+		{name: "dryrun scoped to plan", mode: EnforcementDryrun, scope: EnforcementScopePlan},
+		{name: "warn scoped to plan", mode: EnforcementWarn, scope: EnforcementScopePlan, wantWarn: 1},
+		{name: "dryrun scoped to execute", mode: EnforcementDryrun, scope: EnforcementScopeExecute},
+		{name: "dryrun scoped to all", mode: EnforcementDryrun, scope: EnforcementScopeAll},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &testAction{name: "a", events: []Event{StringEvent("a-done")}, mode: tc.mode, scope: tc.scope}
+
+			ex, err := NewExecutor([]Action{a})
+			if err != nil {
+				t.Fatalf("NewExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if a.ran {
+				t.Errorf("a.ran = true, want false: Scope %s must not let Mode %s run for real", tc.scope, tc.mode)
+			}
+			if len(result.Warnings) != tc.wantWarn {
+				t.Errorf("len(result.Warnings) = %d, want %d", len(result.Warnings), tc.wantWarn)
+			}
+		})
+	}
+}
+
+// TestExecutorEnforcementScopePlanDeny proves that a Deny mode scoped
+// to Plan still aborts Run, not just Execute/All.
+func TestExecutorEnforcementScopePlanDeny(t *testing.T) {
+	denied := &testAction{name: "denied", scope: EnforcementScopePlan, mode: EnforcementDeny, err: errors.New("should never run")}
+
+	ex, err := NewExecutor([]Action{denied})
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+	_, err = ex.Run(context.Background(), nil)
+	var denyErr *DenyError
+	if !errors.As(err, &denyErr) {
+		t.Fatalf("Run() error = %v, want *DenyError", err)
+	}
+	if denied.ran {
+		t.Errorf("denied.ran = true, want false")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Event is something an Action can wait on before it is runnable, or
+// something an Action emits when it completes. Graphs are driven
+// forward by Events: as Actions run, the Events they emit are
+// signaled to every other Action still waiting on them.
+type Event interface {
+	fmt.Stringer
+	// Equal returns true if this Event should be considered the same
+	// signal as o.
+	Equal(o Event) bool
+}
+
+// StringEvent is an Event identified purely by string equality. It is
+// sufficient for most Actions; custom Event types are only needed when
+// equality depends on more than a label (e.g. comparing ResourceIDs).
+type StringEvent string
+
+func (e StringEvent) String() string { return string(e) }
+
+func (e StringEvent) Equal(o Event) bool {
+	other, ok := o.(StringEvent)
+	return ok && e == other
+}
+
+// diffEvents compares two Event slices without regard to order.
+func diffEvents(a, b []Event) string {
+	toStrings := func(evs []Event) []string {
+		ret := make([]string, 0, len(evs))
+		for _, e := range evs {
+			ret = append(ret, e.String())
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	return cmp.Diff(toStrings(a), toStrings(b))
+}
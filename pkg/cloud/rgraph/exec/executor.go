@@ -0,0 +1,303 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec/pubsub"
+)
+
+// defaultPollInterval is how long Run waits between ticks while it has
+// nothing runnable but one or more AsyncActions parked, absent a
+// WithPollInterval override.
+const defaultPollInterval = 5 * time.Second
+
+// executorOptions holds configuration assembled from the
+// ExecutorOptions passed to NewExecutor.
+type executorOptions struct {
+	eventEmitter EventEmitter
+	publishers   *pubsub.Registry
+	policy       EnforcementPolicy
+	pollInterval time.Duration
+}
+
+// ExecutorOption configures optional Executor behavior.
+type ExecutorOption func(*executorOptions) error
+
+func newExecutorOptions(opts ...ExecutorOption) (*executorOptions, error) {
+	o := &executorOptions{
+		eventEmitter: &NoopEventEmitter{},
+		pollInterval: defaultPollInterval,
+	}
+	for _, f := range opts {
+		if err := f(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// WithPollInterval overrides how long Run waits between ticks while
+// waiting on parked AsyncActions with nothing else runnable, instead
+// of the default of defaultPollInterval. It has no effect on callers
+// driving the Executor via Tick directly; those callers own their own
+// pacing.
+func WithPollInterval(d time.Duration) ExecutorOption {
+	return func(o *executorOptions) error {
+		o.pollInterval = d
+		return nil
+	}
+}
+
+// Result summarizes the outcome of an Executor run.
+type Result struct {
+	// Completed lists the Actions that ran to completion, in the
+	// order they finished.
+	Completed []Action
+	// Errors holds the errors returned by any Action that failed.
+	// Actions unaffected by the failure continue to run.
+	Errors []error
+	// Warnings holds one entry for every Action whose effective
+	// EnforcementMode was Warn; such Actions were dry-run rather than
+	// actually executed.
+	Warnings []*WarningEvent
+}
+
+// Executor runs a graph of Actions to completion, respecting the
+// Event dependencies declared between them: an Action only runs once
+// every Action.PendingEvents() it waits on has been signaled by some
+// other Action's output.
+//
+// AsyncAction Actions are handled specially: Tick calls Start once and
+// parks the Action rather than blocking a worker on its completion.
+// Parked Actions are polled once per Tick, starting with the Tick that
+// parked them, until they report StateDone or StateFailed (or Poll
+// returns an error); this requires no change to ActionBase's
+// Signal/CanRun bookkeeping, since a parked Action is simply held out
+// of the runnable set by the Executor until its async work completes.
+type Executor struct {
+	opts *executorOptions
+
+	remaining []Action
+	parked    map[Action]Handle
+	result    *Result
+}
+
+// NewExecutor returns an Executor that will run actions, honoring any
+// ExecutorOptions supplied.
+func NewExecutor(actions []Action, opts ...ExecutorOption) (*Executor, error) {
+	o, err := newExecutorOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("NewExecutor: %w", err)
+	}
+
+	return &Executor{
+		opts:      o,
+		remaining: append([]Action(nil), actions...),
+		parked:    map[Action]Handle{},
+		result:    &Result{},
+	}, nil
+}
+
+// Run ticks the Executor until every Action has completed (or the
+// graph deadlocks), returning the accumulated Result. It returns a
+// DenyError immediately, before running anything, if any Action's
+// effective EnforcementMode is Deny. Between ticks where nothing is
+// runnable but one or more AsyncActions are parked, Run waits for the
+// configured poll interval (see WithPollInterval) rather than
+// re-polling in a tight loop.
+func (ex *Executor) Run(ctx context.Context, c cloud.Cloud) (*Result, error) {
+	if denied := ex.deniedActions(); len(denied) > 0 {
+		return nil, &DenyError{Actions: denied}
+	}
+
+	for {
+		if len(ex.parked) > 0 && !ex.anyRunnable() {
+			select {
+			case <-ctx.Done():
+				return ex.result, ctx.Err()
+			case <-time.After(ex.opts.pollInterval):
+			}
+		}
+
+		done, err := ex.Tick(ctx, c)
+		if err != nil {
+			return ex.result, err
+		}
+		if done {
+			return ex.result, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return ex.result, err
+		}
+	}
+}
+
+// deniedActions returns every Action remaining in the graph whose
+// effective EnforcementMode is Deny.
+func (ex *Executor) deniedActions() []Action {
+	var denied []Action
+	for _, a := range ex.remaining {
+		if effectiveMode(a, ex.opts.policy) == EnforcementDeny {
+			denied = append(denied, a)
+		}
+	}
+	return denied
+}
+
+// anyRunnable returns whether any Action in ex.remaining can run right
+// now.
+func (ex *Executor) anyRunnable() bool {
+	for _, a := range ex.remaining {
+		if a.CanRun() {
+			return true
+		}
+	}
+	return false
+}
+
+// Tick runs one pass over the graph: every currently-runnable Action
+// is either run to completion, dry-run (per EnforcementMode), or
+// started and parked if it is an AsyncAction; every currently-parked
+// Action is polled once. It returns true once no Action remains
+// waiting or parked, and a deadlock error if Actions remain but none
+// of them ran and none is parked. It returns a DenyError, touching nothing, if any
+// remaining Action's effective EnforcementMode is Deny.
+func (ex *Executor) Tick(ctx context.Context, c cloud.Cloud) (bool, error) {
+	if denied := ex.deniedActions(); len(denied) > 0 {
+		return false, &DenyError{Actions: denied}
+	}
+
+	tickActions := ex.remaining
+	var next []Action
+	progressed := false
+
+	for _, a := range tickActions {
+		if !a.CanRun() {
+			next = append(next, a)
+			continue
+		}
+		progressed = true
+
+		resourceIDs := actionResourceIDs(a)
+		consumed := append([]Event(nil), a.DoneEvents()...)
+		emitActionEvent(ctx, ex.opts.eventEmitter, EventTypeActionStarted, a, resourceIDs, nil, nil)
+
+		mode := effectiveMode(a, ex.opts.policy)
+		planOnly := mode == EnforcementDryrun || mode == EnforcementWarn
+
+		if !planOnly {
+			if aa, ok := a.(AsyncAction); ok {
+				handle, err := aa.Start(ctx, c)
+				if err != nil {
+					ex.finishAction(ctx, a, tickActions, resourceIDs, consumed, nil, err)
+					continue
+				}
+				ex.parked[a] = handle
+				continue
+			}
+		}
+
+		var events []Event
+		var err error
+		if planOnly {
+			events = a.DryRun()
+			if mode == EnforcementWarn {
+				ex.result.Warnings = append(ex.result.Warnings, &WarningEvent{Action: a, Events: events})
+			}
+		} else {
+			events, err = a.Run(ctx, c)
+		}
+		ex.finishAction(ctx, a, tickActions, resourceIDs, consumed, events, err)
+	}
+	ex.remaining = next
+
+	for a, h := range ex.parked {
+		aa := a.(AsyncAction)
+		state, events, err := aa.Poll(ctx, h)
+		if state != StateDone && state != StateFailed && err == nil {
+			continue
+		}
+		if state == StateFailed && err == nil {
+			err = fmt.Errorf("%s: async action reported StateFailed", a)
+		}
+		progressed = true
+		delete(ex.parked, a)
+
+		resourceIDs := actionResourceIDs(a)
+		consumed := append([]Event(nil), a.DoneEvents()...)
+		ex.finishAction(ctx, a, ex.remaining, resourceIDs, consumed, events, err)
+	}
+
+	done := len(ex.remaining) == 0 && len(ex.parked) == 0
+	if !progressed && !done && len(ex.parked) == 0 {
+		return false, fmt.Errorf("Executor.Run: deadlock, %d Actions can never become runnable", len(ex.remaining))
+	}
+	return done, nil
+}
+
+// finishAction records a's outcome (success or failure), emits
+// notifications, and, on success, signals the Events it produced to
+// every other Action in others.
+func (ex *Executor) finishAction(ctx context.Context, a Action, others []Action, resourceIDs []*cloud.ResourceID, consumed, events []Event, err error) {
+	if err != nil {
+		ex.result.Errors = append(ex.result.Errors, fmt.Errorf("%s: %w", a, err))
+		emitActionEvent(ctx, ex.opts.eventEmitter, EventTypeActionFailed, a, resourceIDs, events, err)
+		publishActionMessage(ctx, ex.opts.publishers, a, resourceIDStrings(resourceIDs), consumed, events, err)
+		return
+	}
+
+	ex.result.Completed = append(ex.result.Completed, a)
+	emitActionEvent(ctx, ex.opts.eventEmitter, EventTypeActionSucceeded, a, resourceIDs, events, nil)
+	publishActionMessage(ctx, ex.opts.publishers, a, resourceIDStrings(resourceIDs), consumed, events, nil)
+
+	for _, ev := range events {
+		for _, other := range others {
+			if other == a {
+				continue
+			}
+			if other.Signal(ev) {
+				emitActionEvent(ctx, ex.opts.eventEmitter, EventTypeActionEventSignaled, other, actionResourceIDs(other), []Event{ev}, nil)
+			}
+		}
+	}
+}
+
+// actionResourceIDs returns the cloud.ResourceIDs a operates on, if it
+// implements ActionResourceIDs.
+func actionResourceIDs(a Action) []*cloud.ResourceID {
+	rs, ok := a.(ActionResourceIDs)
+	if !ok {
+		return nil
+	}
+	return rs.ResourceIDs()
+}
+
+func resourceIDStrings(ids []*cloud.ResourceID) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, id.String())
+	}
+	return ret
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec/pubsub"
+)
+
+// WithPublisher attaches pub to the Executor. Every Publisher attached
+// this way receives a pubsub.ActionMessage for each Action that
+// finishes running (successfully or not); multiple calls may be
+// supplied to publish the same stream to several backends. Attached
+// Publishers are fanned out to via a pubsub.Registry.
+func WithPublisher(pub pubsub.Publisher) ExecutorOption {
+	return func(o *executorOptions) error {
+		if o.publishers == nil {
+			o.publishers = pubsub.NewRegistry()
+		}
+		o.publishers.Add(pub)
+		return nil
+	}
+}
+
+// publishActionMessage builds a pubsub.ActionMessage for a and
+// publishes it to reg, if one is configured. Publish errors are
+// swallowed for the same reason CloudEvents emission errors are:
+// notification delivery is best-effort and must never fail the Action
+// it describes.
+func publishActionMessage(ctx context.Context, reg *pubsub.Registry, a Action, resourceIDs []string, consumed, fired []Event, runErr error) {
+	if reg == nil {
+		return
+	}
+
+	md := a.Metadata()
+	msg := &pubsub.ActionMessage{
+		Name:           md.Name,
+		Type:           string(md.Type),
+		ResourceIDs:    resourceIDs,
+		EventsConsumed: eventStrings(consumed),
+		EventsFired:    eventStrings(fired),
+	}
+	if runErr != nil {
+		msg.Error = runErr.Error()
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	key := md.Name
+	if len(resourceIDs) > 0 {
+		key = resourceIDs[0]
+	}
+	attrs := map[string]string{"type": msg.Type}
+
+	_ = reg.Publish(ctx, key, data, attrs)
+}
+
+func eventStrings(evs []Event) []string {
+	if len(evs) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(evs))
+	for _, e := range evs {
+		ret = append(ret, e.String())
+	}
+	return ret
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is a single call recorded by a FakePublisher.
+type Message struct {
+	Key   string
+	Data  []byte
+	Attrs map[string]string
+}
+
+// FakePublisher is an in-memory Publisher for use in tests.
+type FakePublisher struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// Publish records the message.
+func (f *FakePublisher) Publish(ctx context.Context, key string, data []byte, attrs map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, Message{Key: key, Data: data, Attrs: attrs})
+	return nil
+}
+
+// Messages returns every message recorded so far, in publish order.
+func (f *FakePublisher) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages...)
+}
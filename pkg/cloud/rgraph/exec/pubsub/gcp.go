@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// gcpPublishDelayThreshold, gcpPublishCountThreshold and
+// gcpPublishByteThreshold match the pubsub client library's own
+// defaults. They are set explicitly, rather than left implicit, so
+// the batching behavior this package relies on is visible here and
+// doesn't silently drift if the library's defaults ever change.
+const (
+	gcpPublishDelayThreshold = 10 * time.Millisecond
+	gcpPublishCountThreshold = 100
+	gcpPublishByteThreshold  = 1e6
+)
+
+// GCPPublisher publishes to a single Cloud Pub/Sub topic, with
+// message ordering enabled so that all messages sharing a key (the
+// Executor uses the affected resource's key) are delivered in publish
+// order, and the client library's batching enabled: Publish hands
+// each message to the library and returns immediately rather than
+// waiting for it to be acknowledged, so messages published in quick
+// succession (e.g. by consecutive Executor.Tick calls) can actually
+// be coalesced into one request. Call Flush, or Stop when done, to
+// observe delivery errors.
+type GCPPublisher struct {
+	topic *pubsub.Topic
+
+	mu      sync.Mutex
+	pending []*pubsub.PublishResult
+}
+
+// NewGCPPublisher returns a Publisher backed by topicID in project
+// projectID. It enables message ordering on the topic; callers are
+// responsible for calling Stop when done to flush pending messages.
+func NewGCPPublisher(ctx context.Context, projectID, topicID string) (*GCPPublisher, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GCPPublisher: creating client: %w", err)
+	}
+	topic := client.Topic(topicID)
+	topic.EnableMessageOrdering = true
+	topic.PublishSettings.DelayThreshold = gcpPublishDelayThreshold
+	topic.PublishSettings.CountThreshold = gcpPublishCountThreshold
+	topic.PublishSettings.ByteThreshold = gcpPublishByteThreshold
+	return &GCPPublisher{topic: topic}, nil
+}
+
+// Publish hands data to the client library as a single Pub/Sub
+// message, using key as the message's OrderingKey, and returns
+// without waiting for it to reach the server. The returned error is
+// always nil; delivery errors surface from Flush or Stop instead.
+func (g *GCPPublisher) Publish(ctx context.Context, key string, data []byte, attrs map[string]string) error {
+	result := g.topic.Publish(ctx, &pubsub.Message{
+		Data:        data,
+		Attributes:  attrs,
+		OrderingKey: key,
+	})
+	g.mu.Lock()
+	g.pending = append(g.pending, result)
+	g.mu.Unlock()
+	return nil
+}
+
+// Flush waits for every message Published so far to reach the
+// server, returning the first error encountered, if any. Messages
+// Published concurrently with Flush may or may not be waited on.
+func (g *GCPPublisher) Flush(ctx context.Context) error {
+	g.mu.Lock()
+	pending := g.pending
+	g.pending = nil
+	g.mu.Unlock()
+
+	var firstErr error
+	for _, r := range pending {
+		if _, err := r.Get(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("GCPPublisher: publish to %s: %w", g.topic.ID(), err)
+		}
+	}
+	return firstErr
+}
+
+// Stop flushes any messages buffered for batching, waits for their
+// delivery, and releases the topic's resources. Delivery errors are
+// discarded; call Flush first if the caller needs to observe them.
+func (g *GCPPublisher) Stop() {
+	_ = g.Flush(context.Background())
+	g.topic.Stop()
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	pb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
+	"cloud.google.com/go/pubsub/pstest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestGCPPublisher starts a fake Pub/Sub server with auto-acking
+// disabled (so the test controls exactly when the server responds to
+// a Publish RPC) and returns a GCPPublisher backed by a topic on it.
+func newTestGCPPublisher(t *testing.T) (*GCPPublisher, *pstest.Server) {
+	t.Helper()
+
+	srv := pstest.NewServer()
+	t.Cleanup(func() { srv.Close() })
+	srv.SetAutoPublishResponse(false)
+
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("grpc.Dial(%s) = %v, want nil", srv.Addr, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client, err := pubsub.NewClient(context.Background(), "proj1", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("pubsub.NewClient() = %v, want nil", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	topic, err := client.CreateTopic(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("CreateTopic() = %v, want nil", err)
+	}
+	topic.EnableMessageOrdering = true
+
+	return &GCPPublisher{topic: topic}, srv
+}
+
+// TestGCPPublisherPublishDoesNotBlock proves that Publish hands the
+// message to the client library and returns before the server
+// responds, which is what lets the library coalesce concurrent
+// Publish calls into one batch instead of serializing them.
+func TestGCPPublisherPublishDoesNotBlock(t *testing.T) {
+	g, srv := newTestGCPPublisher(t)
+
+	done := make(chan error, 1)
+	go func() { done <- g.Publish(context.Background(), "key1", []byte("msg"), nil) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Publish() = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Publish() blocked for 2s waiting on a server response it should never wait for")
+	}
+
+	srv.AddPublishResponse(&pb.PublishResponse{MessageIds: []string{"m1"}}, nil)
+	if err := g.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() = %v, want nil", err)
+	}
+}
+
+// TestGCPPublisherFlushReportsError proves that an error the server
+// eventually returns for a Publish RPC surfaces from Flush, even
+// though Publish itself already returned nil.
+func TestGCPPublisherFlushReportsError(t *testing.T) {
+	g, srv := newTestGCPPublisher(t)
+
+	if err := g.Publish(context.Background(), "key1", []byte("msg"), nil); err != nil {
+		t.Fatalf("Publish() = %v, want nil", err)
+	}
+
+	srv.AddPublishResponse(nil, status.Error(codes.Internal, "injected publish failure"))
+	if err := g.Flush(context.Background()); err == nil {
+		t.Errorf("Flush() = nil, want an error surfaced from the server's response")
+	}
+}
+
+// TestGCPPublisherStopFlushes proves Stop waits for pending publishes
+// instead of dropping them on the floor.
+func TestGCPPublisherStopFlushes(t *testing.T) {
+	g, srv := newTestGCPPublisher(t)
+
+	if err := g.Publish(context.Background(), "key1", []byte("msg"), nil); err != nil {
+		t.Fatalf("Publish() = %v, want nil", err)
+	}
+	srv.AddPublishResponse(&pb.PublishResponse{MessageIds: []string{"m1"}}, nil)
+
+	stopped := make(chan struct{})
+	go func() { g.Stop(); close(stopped) }()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Stop() did not return after its pending publish was acked")
+	}
+}
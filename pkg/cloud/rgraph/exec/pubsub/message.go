@@ -0,0 +1,37 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+// ActionMessage describes a completed Action for publication. It is
+// intentionally independent of the exec package's internal types so
+// that subscribers don't need to import it.
+type ActionMessage struct {
+	// Name is the Action's unique name within its graph.
+	Name string `json:"name"`
+	// Type is the ActionType string (e.g. "Create", "Custom", "Meta").
+	Type string `json:"type"`
+	// ResourceIDs are the resources (as their String() form) the
+	// Action affected.
+	ResourceIDs []string `json:"resourceIDs,omitempty"`
+	// EventsConsumed are the pending Events that were satisfied before
+	// this Action ran.
+	EventsConsumed []string `json:"eventsConsumed,omitempty"`
+	// EventsFired are the Events this Action emitted upon completion.
+	EventsFired []string `json:"eventsFired,omitempty"`
+	// Error is the Action's error, if it failed. Empty on success.
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pubsub lets an Executor publish a structured message for
+// every completed Action to one or more message-bus backends, so that
+// downstream systems can subscribe to reconciliation activity instead
+// of scraping logs.
+package pubsub
+
+import "context"
+
+// Publisher delivers a single message to a message bus. key identifies
+// the ordering domain of the message (the Executor uses the affected
+// resource's key so that per-resource messages stay ordered);
+// implementations that don't support ordering may ignore it.
+type Publisher interface {
+	Publish(ctx context.Context, key string, data []byte, attrs map[string]string) error
+}
+
+// Registry fans a single Publish call out to every Publisher attached
+// to an Executor. It is itself a Publisher, so it can be passed
+// anywhere a single backend is expected.
+type Registry struct {
+	publishers []Publisher
+}
+
+// NewRegistry returns a Registry that publishes to all of publishers.
+func NewRegistry(publishers ...Publisher) *Registry {
+	return &Registry{publishers: publishers}
+}
+
+// Add attaches another Publisher to the Registry.
+func (r *Registry) Add(p Publisher) { r.publishers = append(r.publishers, p) }
+
+// Publish sends data to every attached Publisher, returning the first
+// error encountered (after still attempting the remaining backends).
+func (r *Registry) Publish(ctx context.Context, key string, data []byte, attrs map[string]string) error {
+	var firstErr error
+	for _, p := range r.publishers {
+		if err := p.Publish(ctx, key, data, attrs); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec/pubsub"
+)
+
+func TestExecutorPublishesActionMessages(t *testing.T) {
+	fake := &pubsub.FakePublisher{}
+	a := &testAction{name: "a", events: []Event{StringEvent("done")}}
+
+	ex, err := NewExecutor([]Action{a}, WithPublisher(fake))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	msgs := fake.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("len(msgs) = %d, want 1", len(msgs))
+	}
+	if msgs[0].Key != a.Metadata().Name {
+		t.Errorf("msgs[0].Key = %q, want %q", msgs[0].Key, a.Metadata().Name)
+	}
+}
+
+func TestExecutorPublishesFailure(t *testing.T) {
+	fake := &pubsub.FakePublisher{}
+	a := &testAction{name: "a", err: errors.New("boom")}
+
+	ex, err := NewExecutor([]Action{a}, WithPublisher(fake))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run() = %v, want nil (errors are collected in Result, not returned)", err)
+	}
+
+	msgs := fake.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("len(msgs) = %d, want 1", len(msgs))
+	}
+}
+
+func TestExecutorPublishesToMultiplePublishers(t *testing.T) {
+	fake1 := &pubsub.FakePublisher{}
+	fake2 := &pubsub.FakePublisher{}
+	a := &testAction{name: "a", events: []Event{StringEvent("done")}}
+
+	ex, err := NewExecutor([]Action{a}, WithPublisher(fake1), WithPublisher(fake2))
+	if err != nil {
+		t.Fatalf("NewExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	if len(fake1.Messages()) != 1 {
+		t.Errorf("len(fake1.Messages()) = %d, want 1", len(fake1.Messages()))
+	}
+	if len(fake2.Messages()) != 1 {
+		t.Errorf("len(fake2.Messages()) = %d, want 1", len(fake2.Messages()))
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// webhookStartResponse is the JSON body a webhook controller returns
+// from the initial POST.
+type webhookStartResponse struct {
+	Handle string `json:"handle"`
+}
+
+// webhookPollResponse is the JSON body a webhook controller returns
+// from a poll request.
+type webhookPollResponse struct {
+	State  State    `json:"state"`
+	Events []string `json:"events,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// WebhookAction is a reference AsyncAction implementation that hands
+// work off to an external controller by POSTing a spec to a URL.
+// Start's response supplies the Handle used for subsequent polls; Poll
+// POSTs that Handle back to the same URL and treats the JSON response
+// as the completion state and Event set.
+type WebhookAction struct {
+	ActionBase
+
+	Name string
+	// URL is the webhook endpoint invoked by both Start and Poll.
+	URL string
+	// Spec is marshaled as JSON and POSTed to URL by Start.
+	Spec interface{}
+	// Client is used to make requests. http.DefaultClient is used if
+	// nil.
+	Client *http.Client
+}
+
+// NewWebhookAction returns a WebhookAction that hands spec off to the
+// controller listening at url.
+func NewWebhookAction(name, url string, spec interface{}) *WebhookAction {
+	return &WebhookAction{Name: name, URL: url, Spec: spec}
+}
+
+func (w *WebhookAction) String() string { return fmt.Sprintf("WebhookAction(%s)", w.Name) }
+
+func (w *WebhookAction) Metadata() *ActionMetadata {
+	return &ActionMetadata{
+		Name:    w.Name,
+		Type:    ActionTypeCustomRun,
+		Summary: fmt.Sprintf("Action performed by the controller at %s", w.URL),
+	}
+}
+
+// DryRun returns no Events: what a webhook controller will emit isn't
+// knowable without actually invoking it.
+func (w *WebhookAction) DryRun() []Event { return nil }
+
+// Run always fails: WebhookAction must be driven via Start/Poll by an
+// Executor that understands AsyncAction.
+func (w *WebhookAction) Run(context.Context, cloud.Cloud) ([]Event, error) {
+	return nil, fmt.Errorf("WebhookAction(%s): Run called directly; use an Executor that supports AsyncAction", w.Name)
+}
+
+func (w *WebhookAction) client() *http.Client {
+	if w.Client != nil {
+		return w.Client
+	}
+	return http.DefaultClient
+}
+
+// Start POSTs Spec to URL and returns the Handle from the response.
+func (w *WebhookAction) Start(ctx context.Context, c cloud.Cloud) (Handle, error) {
+	body, err := json.Marshal(w.Spec)
+	if err != nil {
+		return "", fmt.Errorf("WebhookAction(%s): marshal spec: %w", w.Name, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("WebhookAction(%s): build request: %w", w.Name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("WebhookAction(%s): post to %s: %w", w.Name, w.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("WebhookAction(%s): post to %s: status %s", w.Name, w.URL, resp.Status)
+	}
+
+	var start webhookStartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&start); err != nil {
+		return "", fmt.Errorf("WebhookAction(%s): decode start response: %w", w.Name, err)
+	}
+	return Handle(start.Handle), nil
+}
+
+// Poll POSTs h back to URL and returns the reported State and Events.
+func (w *WebhookAction) Poll(ctx context.Context, h Handle) (State, []Event, error) {
+	body, err := json.Marshal(struct {
+		Handle string `json:"handle"`
+	}{Handle: string(h)})
+	if err != nil {
+		return "", nil, fmt.Errorf("WebhookAction(%s): marshal poll request: %w", w.Name, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", nil, fmt.Errorf("WebhookAction(%s): build poll request: %w", w.Name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client().Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("WebhookAction(%s): poll %s: %w", w.Name, w.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", nil, fmt.Errorf("WebhookAction(%s): poll %s: status %s", w.Name, w.URL, resp.Status)
+	}
+
+	var poll webhookPollResponse
+	if err := json.NewDecoder(resp.Body).Decode(&poll); err != nil {
+		return "", nil, fmt.Errorf("WebhookAction(%s): decode poll response: %w", w.Name, err)
+	}
+	if poll.State == StateFailed {
+		return poll.State, nil, fmt.Errorf("WebhookAction(%s): %s", w.Name, poll.Error)
+	}
+	switch poll.State {
+	case StatePending, StateRunning, StateDone:
+	default:
+		return "", nil, fmt.Errorf("WebhookAction(%s): poll %s: unrecognized state %q", w.Name, w.URL, poll.State)
+	}
+
+	events := make([]Event, 0, len(poll.Events))
+	for _, s := range poll.Events {
+		events = append(events, StringEvent(s))
+	}
+	return poll.State, events, nil
+}
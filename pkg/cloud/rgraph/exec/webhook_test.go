@@ -0,0 +1,97 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookActionStartAndPoll(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/poll" {
+			w.Write([]byte(`{"state":"Done","events":["webhook-done"]}`))
+			return
+		}
+		w.Write([]byte(`{"handle":"h1"}`))
+	}))
+	defer srv.Close()
+
+	wa := NewWebhookAction("a", srv.URL, map[string]string{"k": "v"})
+	h, err := wa.Start(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Start() = %v, want nil", err)
+	}
+	if h != "h1" {
+		t.Errorf("Start() handle = %q, want %q", h, "h1")
+	}
+
+	wa.URL = srv.URL + "/poll"
+	state, events, err := wa.Poll(context.Background(), h)
+	if err != nil {
+		t.Fatalf("Poll() = %v, want nil", err)
+	}
+	if state != StateDone {
+		t.Errorf("Poll() state = %q, want %q", state, StateDone)
+	}
+	if len(events) != 1 || events[0].String() != "webhook-done" {
+		t.Errorf("Poll() events = %v, want [webhook-done]", events)
+	}
+}
+
+func TestWebhookActionStartNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error":"upstream controller unreachable"}`))
+	}))
+	defer srv.Close()
+
+	wa := NewWebhookAction("a", srv.URL, nil)
+	if _, err := wa.Start(context.Background(), nil); err == nil {
+		t.Errorf("Start() = nil, want error for 502 response")
+	}
+}
+
+func TestWebhookActionPollNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error":"upstream controller unreachable"}`))
+	}))
+	defer srv.Close()
+
+	wa := NewWebhookAction("a", srv.URL, nil)
+	if _, _, err := wa.Poll(context.Background(), "h1"); err == nil {
+		t.Errorf("Poll() = nil error, want error for 502 response")
+	}
+}
+
+func TestWebhookActionPollGarbageState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state":"Sideways"}`))
+	}))
+	defer srv.Close()
+
+	wa := NewWebhookAction("a", srv.URL, nil)
+	state, _, err := wa.Poll(context.Background(), "h1")
+	if err == nil {
+		t.Errorf("Poll() = (%q, nil), want an error for an unrecognized state", state)
+	}
+}